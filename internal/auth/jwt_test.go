@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hatchet-dev/hatchet/internal/repository"
+)
+
+func TestGenerateAndValidateWebhookWorkerToken(t *testing.T) {
+	m := NewJWTManager([]byte("signing-key"))
+
+	token, err := m.GenerateWebhookWorkerToken(context.Background(), "tenant1", "worker1", &repository.WebhookWorkerTokenClaims{
+		Actions:   []string{"action:a"},
+		Workflows: []string{"workflow:a"},
+	})
+	if err != nil {
+		t.Fatalf("could not generate token: %v", err)
+	}
+
+	claims, err := m.ValidateWebhookWorkerToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("could not validate token: %v", err)
+	}
+
+	if claims.Sub != "worker1" || claims.Aud != "webhook" || claims.TenantID != "tenant1" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+	if len(claims.Actions) != 1 || claims.Actions[0] != "action:a" {
+		t.Fatalf("expected actions to round-trip, got %+v", claims.Actions)
+	}
+}
+
+func TestValidateWebhookWorkerTokenExpired(t *testing.T) {
+	m := NewJWTManager([]byte("signing-key"))
+
+	token, err := m.sign(WebhookWorkerClaims{
+		Sub: "worker1",
+		Aud: "webhook",
+		Exp: time.Now().Add(-time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("could not sign token: %v", err)
+	}
+
+	if _, err := m.ValidateWebhookWorkerToken(context.Background(), token); err == nil {
+		t.Fatalf("expected an expired token to fail validation")
+	}
+}
+
+func TestValidateWebhookWorkerTokenWrongKey(t *testing.T) {
+	m := NewJWTManager([]byte("signing-key"))
+	other := NewJWTManager([]byte("a-different-key"))
+
+	token, err := m.GenerateWebhookWorkerToken(context.Background(), "tenant1", "worker1", &repository.WebhookWorkerTokenClaims{})
+	if err != nil {
+		t.Fatalf("could not generate token: %v", err)
+	}
+
+	if _, err := other.ValidateWebhookWorkerToken(context.Background(), token); err == nil {
+		t.Fatalf("expected a token signed with a different key to fail validation")
+	}
+}
+
+func TestWebhookWorkerTokenScopedToASingleWorker(t *testing.T) {
+	// Mirrors internal/services/webhooks.validateWorkerToken's own check:
+	// a token minted for one worker must not be accepted as scoped to
+	// another, even though both are valid, unexpired tokens from the same
+	// JWTManager.
+	m := NewJWTManager([]byte("signing-key"))
+
+	token, err := m.GenerateWebhookWorkerToken(context.Background(), "tenant1", "worker1", &repository.WebhookWorkerTokenClaims{
+		Actions: []string{"action:a"},
+	})
+	if err != nil {
+		t.Fatalf("could not generate token: %v", err)
+	}
+
+	claims, err := m.ValidateWebhookWorkerToken(context.Background(), token)
+	if err != nil {
+		t.Fatalf("could not validate token: %v", err)
+	}
+
+	if claims.Sub == "worker2" {
+		t.Fatalf("token for worker1 must not validate as scoped to worker2")
+	}
+	if claims.Sub != "worker1" || claims.Aud != "webhook" {
+		t.Fatalf("expected claims scoped to worker1/webhook, got sub=%q aud=%q", claims.Sub, claims.Aud)
+	}
+}
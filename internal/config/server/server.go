@@ -0,0 +1,66 @@
+// Package server holds the shared configuration and wiring every service
+// under internal/services is constructed with.
+package server
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hatchet-dev/hatchet/internal/auth"
+	"github.com/hatchet-dev/hatchet/internal/repository"
+)
+
+// AuthConfig groups the engine's token-issuing/validating dependencies.
+type AuthConfig struct {
+	JWTManager *auth.JWTManager
+}
+
+// WebhooksConfig configures the webhook worker subsystem
+// (internal/services/webhooks and pkg/webhook).
+type WebhooksConfig struct {
+	// ListenAddr is the address the webhook subsystem's own HTTP server
+	// binds for worker-facing routes (heartbeat lease renewal) and
+	// tenant-facing routes (delivery list/replay). Webhook workers are
+	// handed this address (as EngineURL) so they know where to heartbeat.
+	ListenAddr string
+
+	// CircuitBreakerBaseDelay and CircuitBreakerMaxDelay bound the
+	// decorrelated-jitter backoff internal/services/webhooks.breakerStore
+	// uses between probes of a worker whose breaker is open.
+	CircuitBreakerBaseDelay time.Duration
+	CircuitBreakerMaxDelay  time.Duration
+}
+
+// Logger is the minimal structured-ish logging interface services are
+// written against, so they don't depend on a specific logging library.
+type Logger struct {
+	prefix string
+}
+
+// NewLogger constructs a Logger that prefixes every line with prefix (e.g.
+// a service name), the same way each service's log output is tagged today.
+func NewLogger(prefix string) *Logger {
+	return &Logger{prefix: prefix}
+}
+
+func (l *Logger) Printf(format string, args ...interface{}) {
+	log.Printf("[%s] %s", l.prefix, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Err(err error) {
+	if err == nil {
+		return
+	}
+	log.Printf("[%s] error: %v", l.prefix, err)
+}
+
+// ServerConfig is the shared dependency bag passed to every service
+// constructor (e.g. webhooks.New).
+type ServerConfig struct {
+	EngineRepository repository.EngineRepository
+	APIRepository    repository.APIRepository
+	Auth             AuthConfig
+	Logger           *Logger
+	Webhooks         WebhooksConfig
+}
@@ -0,0 +1,224 @@
+// Package memory implements internal/repository's interfaces over plain
+// in-process maps. It exists so the webhook worker subsystem (and anything
+// else written against the repository interfaces) can run end-to-end --
+// in tests, in a single-replica dev deployment, or in this tree, which does
+// not vendor the generated Prisma client -- without a Postgres instance.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hatchet-dev/hatchet/internal/repository"
+	"github.com/hatchet-dev/hatchet/internal/repository/prisma/db"
+)
+
+// engineRepository is the in-memory EngineRepository.
+type engineRepository struct {
+	tenant *tenantRepository
+	worker *workerRepository
+}
+
+// NewEngineRepository constructs an in-memory EngineRepository seeded with
+// the given tenants.
+func NewEngineRepository(tenants []db.TenantModel) repository.EngineRepository {
+	return &engineRepository{
+		tenant: &tenantRepository{tenants: tenants},
+		worker: &workerRepository{
+			workers:  map[string]*db.WebhookWorkerModel{},
+			assigned: map[string][]string{},
+		},
+	}
+}
+
+func (r *engineRepository) Tenant() repository.TenantRepository { return r.tenant }
+func (r *engineRepository) Worker() repository.WorkerRepository { return r.worker }
+
+type tenantRepository struct {
+	tenants []db.TenantModel
+}
+
+func (r *tenantRepository) ListTenants(ctx context.Context) ([]db.TenantModel, error) {
+	return r.tenants, nil
+}
+
+type workerRepository struct {
+	mu       sync.Mutex
+	workers  map[string]*db.WebhookWorkerModel
+	assigned map[string][]string
+}
+
+func (r *workerRepository) key(tenantId, workerId string) string {
+	return tenantId + "/" + workerId
+}
+
+// UpdateWorker applies opts' breaker-state fields, creating the row if it
+// doesn't exist yet. opts.IsActive is accepted but not stored, since
+// WebhookWorkerModel has no active flag.
+func (r *workerRepository) UpdateWorker(ctx context.Context, tenantId, workerId string, opts *repository.UpdateWorkerOpts) (*db.WebhookWorkerModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := r.key(tenantId, workerId)
+	w, ok := r.workers[k]
+	if !ok {
+		w = &db.WebhookWorkerModel{ID: workerId, TenantID: tenantId}
+		r.workers[k] = w
+	}
+
+	if opts.DispatcherState != nil {
+		w.DispatcherState = *opts.DispatcherState
+	}
+	if opts.DispatcherStateSince != nil {
+		w.DispatcherStateSince = *opts.DispatcherStateSince
+	}
+
+	return w, nil
+}
+
+func (r *workerRepository) GetWorker(ctx context.Context, tenantId, workerId string) (*db.WebhookWorkerModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.workers[r.key(tenantId, workerId)]
+	if !ok {
+		return nil, fmt.Errorf("worker %s not found", workerId)
+	}
+
+	cp := *w
+	return &cp, nil
+}
+
+func (r *workerRepository) ListAssignedWorkflowRunIds(ctx context.Context, tenantId, workerId string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.assigned[r.key(tenantId, workerId)], nil
+}
+
+// apiRepository is the in-memory APIRepository.
+type apiRepository struct {
+	webhookWorker   *webhookWorkerRepository
+	webhookDelivery *webhookDeliveryRepository
+}
+
+// NewAPIRepository constructs an in-memory APIRepository seeded with the
+// given webhook workers.
+func NewAPIRepository(workers []db.WebhookWorkerModel) repository.APIRepository {
+	byID := map[string]db.WebhookWorkerModel{}
+	for _, w := range workers {
+		byID[w.ID] = w
+	}
+
+	return &apiRepository{
+		webhookWorker:   &webhookWorkerRepository{byID: byID},
+		webhookDelivery: &webhookDeliveryRepository{byID: map[string]db.WebhookDeliveryModel{}},
+	}
+}
+
+func (r *apiRepository) WebhookWorker() repository.WebhookWorkerRepository { return r.webhookWorker }
+func (r *apiRepository) WebhookDelivery() repository.WebhookDeliveryRepository {
+	return r.webhookDelivery
+}
+
+type webhookWorkerRepository struct {
+	mu   sync.Mutex
+	byID map[string]db.WebhookWorkerModel
+}
+
+func (r *webhookWorkerRepository) ListWebhookWorkers(ctx context.Context, tenantId string) ([]db.WebhookWorkerModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []db.WebhookWorkerModel
+	for _, w := range r.byID {
+		if w.TenantID == tenantId {
+			out = append(out, w)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+
+	return out, nil
+}
+
+func (r *webhookWorkerRepository) GetWebhookWorkerById(ctx context.Context, id string) (db.WebhookWorkerModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.byID[id]
+	if !ok {
+		return db.WebhookWorkerModel{}, fmt.Errorf("webhook worker %s not found", id)
+	}
+
+	return w, nil
+}
+
+type webhookDeliveryRepository struct {
+	mu   sync.Mutex
+	byID map[string]db.WebhookDeliveryModel
+}
+
+func (r *webhookDeliveryRepository) CreateWebhookDelivery(ctx context.Context, d *db.WebhookDeliveryModel) (*db.WebhookDeliveryModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byID[d.ID] = *d
+
+	return d, nil
+}
+
+func (r *webhookDeliveryRepository) ListWebhookDeliveries(ctx context.Context, tenantId string, opts *repository.ListWebhookDeliveriesOpts) ([]db.WebhookDeliveryModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []db.WebhookDeliveryModel
+	for _, d := range r.byID {
+		if d.TenantID != tenantId {
+			continue
+		}
+		if opts != nil {
+			if opts.WorkerID != "" && d.WorkerID != opts.WorkerID {
+				continue
+			}
+			if opts.Action != "" && d.Action != opts.Action {
+				continue
+			}
+			if opts.Status != "" && statusOf(d) != opts.Status {
+				continue
+			}
+		}
+		out = append(out, d)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+
+	return out, nil
+}
+
+func (r *webhookDeliveryRepository) GetWebhookDeliveryById(ctx context.Context, tenantId, id string) (*db.WebhookDeliveryModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.byID[id]
+	if !ok || d.TenantID != tenantId {
+		return nil, fmt.Errorf("webhook delivery %s not found", id)
+	}
+
+	return &d, nil
+}
+
+func statusOf(d db.WebhookDeliveryModel) string {
+	if d.Error != "" {
+		return "error"
+	}
+	if d.EndedAt.IsZero() {
+		return "pending"
+	}
+	if d.StatusCode >= 200 && d.StatusCode < 300 {
+		return "success"
+	}
+	return "failed"
+}
@@ -0,0 +1,58 @@
+// Package db holds the generated-model-shaped types that the rest of the
+// tree reads and writes. In the full engine these are produced by the
+// Prisma client; this package hand-declares the subset the webhook worker
+// subsystem touches so it can be built and tested without a generated
+// client or a live database.
+package db
+
+import "time"
+
+// UUID is the string-encoded form of a Postgres uuid column, as returned by
+// the generated Prisma client.
+type UUID string
+
+// TenantModel mirrors the columns of the Tenant table that the webhook
+// worker subsystem reads.
+type TenantModel struct {
+	ID UUID
+}
+
+// WebhookWorkerModel mirrors the columns of the WebhookWorker table.
+type WebhookWorkerModel struct {
+	ID        string
+	TenantID  string
+	URL       string
+	Secret    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// SignatureVersion pins which request/response HMAC construction
+	// (internal/services/webhooks.signRequest/signResponse) a worker's
+	// healthchecks and heartbeats are signed with. Empty is treated as "v1"
+	// so existing rows don't need a backfill before this column is read.
+	SignatureVersion string
+
+	// DispatcherState and DispatcherStateSince persist the worker's circuit
+	// breaker state (internal/services/webhooks.breaker) across controller
+	// restarts, so a restart doesn't reset every worker's backoff back to
+	// closed.
+	DispatcherState      string
+	DispatcherStateSince time.Time
+}
+
+// WebhookDeliveryModel mirrors the columns of the WebhookDelivery table,
+// the persisted counterpart to internal/services/webhooks/delivery.Delivery.
+type WebhookDeliveryModel struct {
+	ID            string
+	TenantID      string
+	WorkerID      string
+	Action        string
+	WorkflowRunID string
+	StartedAt     time.Time
+	EndedAt       time.Time
+	StatusCode    int
+	AttemptCount  int
+	RequestHash   string
+	ResponseBody  []byte
+	Error         string
+}
@@ -0,0 +1,11 @@
+// Package sqlchelpers holds small conversion helpers shared by repository
+// implementations, the same way the generated Prisma client's callers in
+// the full engine avoid repeating uuid<->string conversions inline.
+package sqlchelpers
+
+import "github.com/hatchet-dev/hatchet/internal/repository/prisma/db"
+
+// UUIDToStr converts a Prisma uuid column value to its string form.
+func UUIDToStr(id db.UUID) string {
+	return string(id)
+}
@@ -0,0 +1,87 @@
+// Package repository defines the data-access interfaces the services layer
+// depends on, and the option structs used to call them. internal/services
+// code is written against these interfaces rather than the prisma/db
+// package directly, so a service can be exercised against an in-memory
+// implementation (internal/repository/memory) in tests and small
+// deployments without a Postgres instance.
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/hatchet-dev/hatchet/internal/repository/prisma/db"
+)
+
+// UpdateWorkerOpts is a partial update to a worker row. Nil fields are left
+// unchanged.
+type UpdateWorkerOpts struct {
+	IsActive *bool
+
+	// DispatcherState and DispatcherStateSince persist a breaker's state and
+	// last-transition time onto the worker row (see
+	// internal/services/webhooks.persistBreakerState/loadBreakerState).
+	DispatcherState      *string
+	DispatcherStateSince *time.Time
+}
+
+// WebhookWorkerTokenClaims scopes a webhook worker's auth token to exactly
+// the actions/workflows it reported on its last healthcheck or heartbeat.
+type WebhookWorkerTokenClaims struct {
+	Actions   []string
+	Workflows []string
+}
+
+// ListWebhookDeliveriesOpts filters GET .../webhook-deliveries. Empty
+// fields are not applied as filters.
+type ListWebhookDeliveriesOpts struct {
+	WorkerID string
+	Status   string
+	Action   string
+}
+
+// WorkerRepository is the subset of worker persistence the webhook
+// subsystem needs: updating liveness/breaker state, and reading back which
+// workflow runs are currently assigned so a heartbeat response can report
+// backpressure.
+type WorkerRepository interface {
+	UpdateWorker(ctx context.Context, tenantId, workerId string, opts *UpdateWorkerOpts) (*db.WebhookWorkerModel, error)
+	GetWorker(ctx context.Context, tenantId, workerId string) (*db.WebhookWorkerModel, error)
+	ListAssignedWorkflowRunIds(ctx context.Context, tenantId, workerId string) ([]string, error)
+}
+
+// TenantRepository lists tenants for the bootstrap sweep in
+// internal/services/webhooks.check.
+type TenantRepository interface {
+	ListTenants(ctx context.Context) ([]db.TenantModel, error)
+}
+
+// EngineRepository groups the engine-side repositories: the ones backed by
+// the same database the task engine itself runs against.
+type EngineRepository interface {
+	Tenant() TenantRepository
+	Worker() WorkerRepository
+}
+
+// WebhookWorkerRepository is the API-side repository for WebhookWorker
+// rows -- the tenant-facing configuration of a webhook endpoint, as
+// opposed to WorkerRepository's engine-side liveness bookkeeping.
+type WebhookWorkerRepository interface {
+	ListWebhookWorkers(ctx context.Context, tenantId string) ([]db.WebhookWorkerModel, error)
+	GetWebhookWorkerById(ctx context.Context, id string) (db.WebhookWorkerModel, error)
+}
+
+// WebhookDeliveryRepository persists WebhookDelivery rows, backing the list
+// and replay APIs beyond the in-memory Tracker's retention window.
+type WebhookDeliveryRepository interface {
+	CreateWebhookDelivery(ctx context.Context, d *db.WebhookDeliveryModel) (*db.WebhookDeliveryModel, error)
+	ListWebhookDeliveries(ctx context.Context, tenantId string, opts *ListWebhookDeliveriesOpts) ([]db.WebhookDeliveryModel, error)
+	GetWebhookDeliveryById(ctx context.Context, tenantId, id string) (*db.WebhookDeliveryModel, error)
+}
+
+// APIRepository groups the repositories backing the tenant-facing API,
+// mirroring EngineRepository's grouping on the engine side.
+type APIRepository interface {
+	WebhookWorker() WebhookWorkerRepository
+	WebhookDelivery() WebhookDeliveryRepository
+}
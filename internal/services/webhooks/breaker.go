@@ -0,0 +1,245 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/hatchet-dev/hatchet/internal/config/server"
+	"github.com/hatchet-dev/hatchet/internal/repository"
+	"github.com/hatchet-dev/hatchet/internal/repository/prisma/db"
+)
+
+type breakerState string
+
+const (
+	// breakerClosed is the normal state: the worker is dispatching work and
+	// its lease is expected to keep renewing on schedule.
+	breakerClosed breakerState = "closed"
+	// breakerOpen means the worker's lease expired (or a probe failed) and
+	// dispatch has stopped. The breaker keeps probing on a backoff schedule
+	// rather than leaving the worker dead forever.
+	breakerOpen breakerState = "open"
+	// breakerHalfOpen means a backoff window elapsed and a single probe is
+	// in flight to decide whether to restore dispatch (-> closed) or go
+	// back to open with a longer backoff.
+	breakerHalfOpen breakerState = "half-open"
+)
+
+// breaker is a per-worker circuit breaker that replaces the old "three
+// failed healthchecks -> hard flip IsActive=false" policy. A flaky worker
+// backs off exponentially instead of being marked dead forever, and a
+// controller restart doesn't reset its backoff because state is persisted
+// on the worker row (see persist/loadBreakerState).
+type breaker struct {
+	state          breakerState
+	backoff        time.Duration
+	nextProbeAt    time.Time
+	lastTransition time.Time
+}
+
+// breakerStore holds one breaker per worker ID.
+type breakerStore struct {
+	mu       sync.Mutex
+	breakers map[string]*breaker
+	cfg      server.WebhooksConfig
+
+	// load looks up a worker's persisted DispatcherState/DispatcherStateSince,
+	// set once via setLoader. get() consults it the first time it sees a
+	// workerId, so a controller restart resumes a worker's backoff instead
+	// of defaulting it back to closed.
+	load func(workerId string) (*breaker, bool)
+}
+
+// defaultCircuitBreakerBaseDelay and defaultCircuitBreakerMaxDelay are used
+// whenever a WebhooksConfig doesn't set its own bounds, so a zero-value
+// config (e.g. a ServerConfig whose Webhooks field is left unset) still backs
+// off instead of computing a zero delay on every failure forever.
+const (
+	defaultCircuitBreakerBaseDelay = 10 * time.Second
+	defaultCircuitBreakerMaxDelay  = 10 * time.Minute
+)
+
+func newBreakerStore(cfg server.WebhooksConfig) *breakerStore {
+	if cfg.CircuitBreakerBaseDelay <= 0 {
+		cfg.CircuitBreakerBaseDelay = defaultCircuitBreakerBaseDelay
+	}
+	if cfg.CircuitBreakerMaxDelay <= 0 {
+		cfg.CircuitBreakerMaxDelay = defaultCircuitBreakerMaxDelay
+	}
+
+	return &breakerStore{
+		breakers: map[string]*breaker{},
+		cfg:      cfg,
+	}
+}
+
+// setLoader installs the persisted-state loader. Called once from
+// WebhooksController.New, after the store and controller both exist.
+func (s *breakerStore) setLoader(load func(workerId string) (*breaker, bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.load = load
+}
+
+func (s *breakerStore) get(workerId string) *breaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.getLocked(workerId)
+}
+
+// getLocked is get's body, factored out so recordFailure/recordSuccess/
+// beginProbe can also consult the persisted-state loader on a worker's
+// first touch instead of only doing so through get/shouldProbe -- a
+// worker's first touch this session is just as likely to be a failure
+// (sweepExpiredLeases) as a successful probe. Callers must hold s.mu.
+func (s *breakerStore) getLocked(workerId string) *breaker {
+	b, ok := s.breakers[workerId]
+	if !ok && s.load != nil {
+		b, ok = s.load(workerId)
+	}
+	if !ok {
+		b = &breaker{state: breakerClosed, lastTransition: time.Now()}
+	}
+
+	s.breakers[workerId] = b
+
+	return b
+}
+
+// shouldProbe reports whether an open breaker's backoff window has elapsed
+// and it's time to try a half-open probe.
+func (s *breakerStore) shouldProbe(workerId string) bool {
+	b := s.get(workerId)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return b.state == breakerOpen && time.Now().After(b.nextProbeAt)
+}
+
+// recordFailure transitions a breaker to open (from closed or half-open)
+// and schedules the next probe using decorrelated jitter: next =
+// min(cap, random(base, prev*3)). This spreads out retries across flaky
+// workers instead of having them all retry in lockstep.
+func (s *breakerStore) recordFailure(workerId string) *breaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.getLocked(workerId)
+
+	prev := b.backoff
+	if prev == 0 {
+		prev = s.cfg.CircuitBreakerBaseDelay
+	}
+
+	lo := s.cfg.CircuitBreakerBaseDelay
+	hi := prev * 3
+	if hi < lo {
+		hi = lo
+	}
+
+	next := lo + time.Duration(rand.Int63n(int64(hi-lo+1)))
+	if next > s.cfg.CircuitBreakerMaxDelay {
+		next = s.cfg.CircuitBreakerMaxDelay
+	}
+
+	b.state = breakerOpen
+	b.backoff = next
+	b.nextProbeAt = time.Now().Add(next)
+	b.lastTransition = time.Now()
+
+	return b
+}
+
+// recordSuccess closes the breaker, resetting its backoff. If it was open
+// or half-open this is the transition that restores dispatch.
+func (s *breakerStore) recordSuccess(workerId string) *breaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.getLocked(workerId)
+
+	b.state = breakerClosed
+	b.backoff = 0
+	b.nextProbeAt = time.Time{}
+	b.lastTransition = time.Now()
+
+	return b
+}
+
+// beginProbe marks a breaker half-open while a trial probe is in flight.
+func (s *breakerStore) beginProbe(workerId string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.getLocked(workerId)
+
+	b.state = breakerHalfOpen
+	b.lastTransition = time.Now()
+}
+
+// emitTransition logs a structured event for every breaker state change so
+// tenants can alert on a worker flapping, rather than only finding out once
+// it's been dead for a while.
+func (c *WebhooksController) emitTransition(tenantId, workerId string, b *breaker) {
+	c.sc.Logger.Printf(
+		"webhook worker circuit breaker transition: tenant=%s worker=%s state=%s backoff=%s at=%s",
+		tenantId, workerId, b.state, b.backoff, b.lastTransition.Format(time.RFC3339),
+	)
+}
+
+// loadBreakerState is installed as the breakerStore's loader in New(). It
+// reads a worker's persisted DispatcherState/DispatcherStateSince back from
+// the worker row the first time the store sees that workerId, so a
+// controller restart resumes a flaky worker's backoff instead of treating
+// it as freshly closed. tenantId comes from knownWorkers, which every call
+// site populates before it can reach a breakerStore.get() for a new
+// workerId (see HandleWorkerHeartbeat and probeOpenBreakers).
+func (c *WebhooksController) loadBreakerState(workerId string) (*breaker, bool) {
+	v, ok := c.knownWorkers.Load(workerId)
+	if !ok {
+		return nil, false
+	}
+	ww := v.(db.WebhookWorkerModel)
+
+	worker, err := c.sc.EngineRepository.Worker().GetWorker(context.Background(), ww.TenantID, workerId)
+	if err != nil || worker.DispatcherState == "" {
+		return nil, false
+	}
+
+	b := &breaker{
+		state:          breakerState(worker.DispatcherState),
+		lastTransition: worker.DispatcherStateSince,
+	}
+
+	// The backoff duration itself isn't a persisted column (only state and
+	// the last-transition time are) -- on restart, a breaker left open stays
+	// open with its backoff set to the base delay, so its next probe is due
+	// one base delay after the persisted transition time. The pre-restart
+	// backoff length is not restored.
+	if b.state == breakerOpen {
+		b.backoff = c.breaker.cfg.CircuitBreakerBaseDelay
+		b.nextProbeAt = b.lastTransition.Add(b.backoff)
+	}
+
+	return b, true
+}
+
+// persistBreakerState writes the breaker's state and last-transition time
+// onto the worker row, so a controller restart in a multi-replica deploy
+// picks the backoff back up instead of resetting every worker to closed.
+func (c *WebhooksController) persistBreakerState(ctx context.Context, tenantId, workerId string, b *breaker) {
+	state := string(b.state)
+	_, err := c.sc.EngineRepository.Worker().UpdateWorker(ctx, tenantId, workerId, &repository.UpdateWorkerOpts{
+		DispatcherState:      &state,
+		DispatcherStateSince: &b.lastTransition,
+	})
+	if err != nil {
+		c.sc.Logger.Err(fmt.Errorf("could not persist circuit breaker state: %v", err))
+	}
+}
@@ -0,0 +1,124 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hatchet-dev/hatchet/internal/config/server"
+)
+
+func TestBreakerStoreClosedOpenHalfOpenClosed(t *testing.T) {
+	s := newBreakerStore(server.WebhooksConfig{})
+
+	b := s.get("w1")
+	if b.state != breakerClosed {
+		t.Fatalf("expected a fresh breaker to start closed, got %s", b.state)
+	}
+
+	b = s.recordFailure("w1")
+	if b.state != breakerOpen {
+		t.Fatalf("expected recordFailure to open the breaker, got %s", b.state)
+	}
+	if b.backoff <= 0 {
+		t.Fatalf("expected recordFailure to set a positive backoff, got %s", b.backoff)
+	}
+	if s.shouldProbe("w1") {
+		t.Fatalf("expected shouldProbe to be false before the backoff window elapses")
+	}
+
+	s.beginProbe("w1")
+	b = s.get("w1")
+	if b.state != breakerHalfOpen {
+		t.Fatalf("expected beginProbe to move the breaker to half-open, got %s", b.state)
+	}
+
+	b = s.recordSuccess("w1")
+	if b.state != breakerClosed || b.backoff != 0 {
+		t.Fatalf("expected recordSuccess to close the breaker and reset backoff, got %+v", b)
+	}
+}
+
+func TestBreakerStoreRecordFailureBacksOffAcrossCap(t *testing.T) {
+	s := newBreakerStore(server.WebhooksConfig{
+		CircuitBreakerBaseDelay: time.Second,
+		CircuitBreakerMaxDelay:  2 * time.Second,
+	})
+
+	for i := 0; i < 10; i++ {
+		b := s.recordFailure("w1")
+		if b.backoff > 2*time.Second {
+			t.Fatalf("expected backoff to stay within CircuitBreakerMaxDelay, got %s", b.backoff)
+		}
+		if b.backoff < time.Second {
+			t.Fatalf("expected backoff to stay at or above CircuitBreakerBaseDelay, got %s", b.backoff)
+		}
+	}
+}
+
+func TestNewBreakerStoreDefaultsZeroValueConfig(t *testing.T) {
+	s := newBreakerStore(server.WebhooksConfig{})
+
+	if s.cfg.CircuitBreakerBaseDelay <= 0 {
+		t.Fatalf("expected a zero-value config to get a default base delay")
+	}
+	if s.cfg.CircuitBreakerMaxDelay <= 0 {
+		t.Fatalf("expected a zero-value config to get a default max delay")
+	}
+
+	b := s.recordFailure("w1")
+	if b.backoff <= 0 {
+		t.Fatalf("expected a defaulted config to still produce a positive backoff, got %s", b.backoff)
+	}
+}
+
+func TestBreakerStoreRecordFailureAndSuccessConsultLoaderOnFirstTouch(t *testing.T) {
+	// recordFailure and recordSuccess must consult the loader the first
+	// time they see a workerId, the same as get/shouldProbe -- a worker
+	// whose first touch this session is a failed lease sweep (not a
+	// successful healthcheck) must still resume its persisted state.
+	loadedAt := time.Now().Add(-time.Minute)
+	loaded := &breaker{state: breakerOpen, backoff: 5 * time.Second, lastTransition: loadedAt}
+	s := newBreakerStore(server.WebhooksConfig{CircuitBreakerBaseDelay: time.Second})
+	s.setLoader(func(workerId string) (*breaker, bool) {
+		if workerId != "restarted" {
+			return nil, false
+		}
+		return loaded, true
+	})
+
+	b := s.recordFailure("restarted")
+	if b.lastTransition.Equal(loadedAt) {
+		t.Fatalf("expected recordFailure to update lastTransition, not leave the loaded value")
+	}
+	// The important assertion is that recordFailure went through the
+	// loader at all rather than silently starting a fresh closed breaker
+	// for a worker the loader already knows about.
+	if got := s.get("restarted"); got != loaded || !got.lastTransition.After(loadedAt) {
+		t.Fatalf("expected the loaded breaker to have been the one mutated")
+	}
+
+	loaded2 := &breaker{state: breakerOpen, backoff: 5 * time.Second, lastTransition: loadedAt}
+	s2 := newBreakerStore(server.WebhooksConfig{CircuitBreakerBaseDelay: time.Second})
+	s2.setLoader(func(workerId string) (*breaker, bool) {
+		return loaded2, true
+	})
+	b2 := s2.recordSuccess("restarted")
+	if b2 != loaded2 || b2.state != breakerClosed {
+		t.Fatalf("expected recordSuccess to close a breaker loaded from persisted state, got %s", b2.state)
+	}
+}
+
+func TestBreakerStoreBeginProbeConsultsLoader(t *testing.T) {
+	s := newBreakerStore(server.WebhooksConfig{CircuitBreakerBaseDelay: time.Second})
+	loaded := &breaker{state: breakerOpen, lastTransition: time.Now()}
+	s.setLoader(func(workerId string) (*breaker, bool) {
+		return loaded, true
+	})
+
+	s.beginProbe("never-touched-before")
+
+	b := s.get("never-touched-before")
+	if b != loaded || b.state != breakerHalfOpen {
+		t.Fatalf("expected beginProbe to load persisted state before transitioning, got %s", b.state)
+	}
+}
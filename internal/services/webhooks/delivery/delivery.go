@@ -0,0 +1,125 @@
+// Package delivery tracks individual webhook invocations dispatched by
+// pkg/webhook.Worker, modeled on gitdeploy's job tracking: a delivery moves
+// through Pending -> Actives -> Recents as it's dispatched, runs, and
+// completes, with Recents trimmed on a retention window once persisted.
+package delivery
+
+import (
+	"sync"
+	"time"
+)
+
+// maxResponseBytes bounds how much of a worker's response body is kept on a
+// Delivery record, so a misbehaving worker returning a huge body can't blow
+// up memory or the persisted row.
+const maxResponseBytes = 16 * 1024
+
+// Delivery records a single dispatch of an action to a webhook worker.
+type Delivery struct {
+	ID            string
+	WorkerID      string
+	TenantID      string
+	Action        string
+	WorkflowRunID string
+	StartedAt     time.Time
+	EndedAt       time.Time
+	StatusCode    int
+	AttemptCount  int
+	RequestHash   string
+	ResponseBody  []byte
+	Error         string
+
+	// RequestBody is the original dispatched payload, kept in-memory only
+	// (not a persisted column -- RequestHash is what's written to the
+	// WebhookDelivery table) so the replay API can re-dispatch it without
+	// round-tripping through whatever produced it originally. Once the
+	// delivery falls out of Recents this is gone and replay must fail.
+	RequestBody []byte
+}
+
+// Tracker holds the in-memory view of in-flight and recently-completed
+// deliveries. Persisted history (for the list/replay API) lives in
+// internal/repository; Tracker exists so the dispatch path itself never
+// blocks on a database round-trip to know what's currently outstanding.
+type Tracker struct {
+	Pending sync.Map // map[string]*Delivery, keyed by ID, not yet dispatched
+	Actives sync.Map // map[string]*Delivery, keyed by ID, awaiting a response
+	Recents sync.Map // map[string]*Delivery, keyed by ID, completed within the retention window
+
+	retention time.Duration
+}
+
+// NewTracker creates a Tracker that keeps completed deliveries in Recents
+// for the given retention window before GC trims them.
+func NewTracker(retention time.Duration) *Tracker {
+	if retention <= 0 {
+		retention = 24 * time.Hour
+	}
+
+	return &Tracker{retention: retention}
+}
+
+// Enqueue records a delivery as pending, before it has been dispatched.
+func (t *Tracker) Enqueue(d *Delivery) {
+	t.Pending.Store(d.ID, d)
+}
+
+// Start moves a delivery from Pending to Actives as it's dispatched.
+func (t *Tracker) Start(d *Delivery) {
+	t.Pending.Delete(d.ID)
+	t.Actives.Store(d.ID, d)
+}
+
+// Complete moves a delivery from Actives to Recents, recording its outcome.
+// The response body is truncated to maxResponseBytes.
+func (t *Tracker) Complete(id string, statusCode int, responseBody []byte, dispatchErr error) {
+	v, ok := t.Actives.Load(id)
+	if !ok {
+		return
+	}
+	d := v.(*Delivery)
+
+	t.Actives.Delete(id)
+
+	d.EndedAt = time.Now()
+	d.StatusCode = statusCode
+	if len(responseBody) > maxResponseBytes {
+		responseBody = responseBody[:maxResponseBytes]
+	}
+	d.ResponseBody = responseBody
+	if dispatchErr != nil {
+		d.Error = dispatchErr.Error()
+	}
+
+	t.Recents.Store(id, d)
+}
+
+// GC drops completed deliveries from Recents once they're older than the
+// tracker's retention window. Callers are expected to have already
+// persisted the record via internal/repository before it's dropped here.
+func (t *Tracker) GC() {
+	cutoff := time.Now().Add(-t.retention)
+
+	t.Recents.Range(func(key, value interface{}) bool {
+		d := value.(*Delivery)
+		if d.EndedAt.Before(cutoff) {
+			t.Recents.Delete(key)
+		}
+		return true
+	})
+}
+
+// Get returns a delivery by ID from whichever of the three maps holds it.
+func (t *Tracker) Get(id string) (*Delivery, bool) {
+	if v, ok := t.Pending.Load(id); ok {
+		return v.(*Delivery), true
+	}
+	if v, ok := t.Actives.Load(id); ok {
+		return v.(*Delivery), true
+	}
+	if v, ok := t.Recents.Load(id); ok {
+		return v.(*Delivery), true
+	}
+
+	return nil, false
+}
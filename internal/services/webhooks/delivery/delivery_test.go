@@ -0,0 +1,130 @@
+package delivery
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errDispatch = errors.New("dispatch failed with status code 500")
+
+func TestTrackerEnqueueStartComplete(t *testing.T) {
+	tr := NewTracker(time.Hour)
+
+	d := &Delivery{ID: "d1", AttemptCount: 1, RequestBody: []byte(`{"a":1}`)}
+	tr.Enqueue(d)
+
+	if _, ok := tr.Get("d1"); !ok {
+		t.Fatalf("expected a pending delivery to be visible via Get")
+	}
+	if _, ok := tr.Pending.Load("d1"); !ok {
+		t.Fatalf("expected delivery to be in Pending after Enqueue")
+	}
+
+	tr.Start(d)
+	if _, ok := tr.Pending.Load("d1"); ok {
+		t.Fatalf("expected delivery to leave Pending after Start")
+	}
+	if _, ok := tr.Actives.Load("d1"); !ok {
+		t.Fatalf("expected delivery to be in Actives after Start")
+	}
+
+	tr.Complete("d1", 200, []byte("ok"), nil)
+	if _, ok := tr.Actives.Load("d1"); ok {
+		t.Fatalf("expected delivery to leave Actives after Complete")
+	}
+
+	got, ok := tr.Recents.Load("d1")
+	if !ok {
+		t.Fatalf("expected delivery to be in Recents after Complete")
+	}
+	completed := got.(*Delivery)
+	if completed.StatusCode != 200 || string(completed.ResponseBody) != "ok" || completed.Error != "" {
+		t.Fatalf("unexpected completed delivery: %+v", completed)
+	}
+	if completed.EndedAt.IsZero() {
+		t.Fatalf("expected EndedAt to be set on completion")
+	}
+}
+
+func TestTrackerCompleteRecordsDispatchError(t *testing.T) {
+	tr := NewTracker(time.Hour)
+
+	d := &Delivery{ID: "d1"}
+	tr.Enqueue(d)
+	tr.Start(d)
+	tr.Complete("d1", 500, []byte("boom"), errDispatch)
+
+	got, ok := tr.Get("d1")
+	if !ok {
+		t.Fatalf("expected completed delivery to still be retrievable")
+	}
+	if got.Error != errDispatch.Error() {
+		t.Fatalf("expected dispatch error to be recorded, got %q", got.Error)
+	}
+}
+
+func TestTrackerCompleteTruncatesOversizedResponseBody(t *testing.T) {
+	tr := NewTracker(time.Hour)
+
+	d := &Delivery{ID: "d1"}
+	tr.Enqueue(d)
+	tr.Start(d)
+
+	huge := make([]byte, maxResponseBytes*2)
+	tr.Complete("d1", 200, huge, nil)
+
+	got, _ := tr.Get("d1")
+	if len(got.ResponseBody) != maxResponseBytes {
+		t.Fatalf("expected response body to be truncated to %d bytes, got %d", maxResponseBytes, len(got.ResponseBody))
+	}
+}
+
+func TestTrackerGetAcrossAllStages(t *testing.T) {
+	tr := NewTracker(time.Hour)
+
+	if _, ok := tr.Get("missing"); ok {
+		t.Fatalf("expected Get to miss for an unknown delivery")
+	}
+
+	d := &Delivery{ID: "d1"}
+	tr.Enqueue(d)
+	if _, ok := tr.Get("d1"); !ok {
+		t.Fatalf("expected Get to find a pending delivery")
+	}
+
+	tr.Start(d)
+	if _, ok := tr.Get("d1"); !ok {
+		t.Fatalf("expected Get to find an active delivery")
+	}
+
+	tr.Complete("d1", 200, nil, nil)
+	if _, ok := tr.Get("d1"); !ok {
+		t.Fatalf("expected Get to find a completed delivery")
+	}
+}
+
+func TestTrackerGCDropsOnlyExpiredRecents(t *testing.T) {
+	tr := NewTracker(time.Minute)
+
+	stale := &Delivery{ID: "stale", EndedAt: time.Now().Add(-time.Hour)}
+	fresh := &Delivery{ID: "fresh", EndedAt: time.Now()}
+	tr.Recents.Store(stale.ID, stale)
+	tr.Recents.Store(fresh.ID, fresh)
+
+	tr.GC()
+
+	if _, ok := tr.Recents.Load("stale"); ok {
+		t.Fatalf("expected stale delivery to be GC'd")
+	}
+	if _, ok := tr.Recents.Load("fresh"); !ok {
+		t.Fatalf("expected fresh delivery to survive GC")
+	}
+}
+
+func TestNewTrackerDefaultsNonPositiveRetention(t *testing.T) {
+	tr := NewTracker(0)
+	if tr.retention != 24*time.Hour {
+		t.Fatalf("expected a zero retention to default to 24h, got %s", tr.retention)
+	}
+}
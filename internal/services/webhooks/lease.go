@@ -0,0 +1,85 @@
+package webhooks
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultLeaseTTL is how long a worker's heartbeat lease is valid for before
+// it is considered expired and the worker is marked inactive. Workers are
+// expected to renew well inside this window (see heartbeat interval on the
+// pkg/webhook.Worker side).
+const defaultLeaseTTL = 30 * time.Second
+
+// lease tracks the last heartbeat received from a webhook worker.
+type lease struct {
+	tenantId      string
+	expiresAt     time.Time
+	actionsHash   string
+	workflowsHash string
+	version       string
+	capacity      int
+}
+
+// leaseStore is an in-memory registry of active worker leases, keyed by
+// ww.ID. It replaces the old fixed-interval healthcheck ticker: instead of
+// the engine polling every worker on a schedule, workers push heartbeats and
+// the engine only reacts when a lease is renewed, changes, or expires.
+type leaseStore struct {
+	leases sync.Map // map[string]*lease
+}
+
+func newLeaseStore() *leaseStore {
+	return &leaseStore{}
+}
+
+// renew records a heartbeat for workerId, returning the previous lease (if
+// any) so the caller can diff actions/workflows hashes to detect drift.
+func (s *leaseStore) renew(workerId, tenantId string, h *HealthCheckResponse, version string, capacity int) (prev *lease) {
+	next := &lease{
+		tenantId:      tenantId,
+		expiresAt:     time.Now().Add(defaultLeaseTTL),
+		actionsHash:   hash(h.Actions),
+		workflowsHash: hash(h.Workflows),
+		version:       version,
+		capacity:      capacity,
+	}
+
+	if v, ok := s.leases.Load(workerId); ok {
+		prev = v.(*lease)
+	}
+
+	s.leases.Store(workerId, next)
+
+	return prev
+}
+
+func (s *leaseStore) get(workerId string) (*lease, bool) {
+	v, ok := s.leases.Load(workerId)
+	if !ok {
+		return nil, false
+	}
+
+	return v.(*lease), true
+}
+
+func (s *leaseStore) delete(workerId string) {
+	s.leases.Delete(workerId)
+}
+
+// expired returns the ids of every worker whose lease has not been renewed
+// in time.
+func (s *leaseStore) expired() []string {
+	var ids []string
+
+	now := time.Now()
+	s.leases.Range(func(key, value interface{}) bool {
+		l := value.(*lease)
+		if now.After(l.expiresAt) {
+			ids = append(ids, key.(string))
+		}
+		return true
+	})
+
+	return ids
+}
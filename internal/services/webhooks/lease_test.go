@@ -0,0 +1,59 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLeaseStoreRenewAndExpire(t *testing.T) {
+	s := newLeaseStore()
+
+	if _, ok := s.get("w1"); ok {
+		t.Fatalf("expected no lease for an unknown worker")
+	}
+
+	h := &HealthCheckResponse{Actions: []string{"a"}, Workflows: []string{"wf"}}
+	prev := s.renew("w1", "tenant1", h, "v1", 2)
+	if prev != nil {
+		t.Fatalf("expected no previous lease on first renew, got %+v", prev)
+	}
+
+	l, ok := s.get("w1")
+	if !ok {
+		t.Fatalf("expected a lease for w1 after renew")
+	}
+	if l.tenantId != "tenant1" || l.capacity != 2 || l.version != "v1" {
+		t.Fatalf("unexpected lease fields: %+v", l)
+	}
+	if len(s.expired()) != 0 {
+		t.Fatalf("expected no expired leases immediately after renew")
+	}
+
+	prev = s.renew("w1", "tenant1", h, "v1", 3)
+	if prev == nil || prev.capacity != 2 {
+		t.Fatalf("expected renew to return the prior lease, got %+v", prev)
+	}
+}
+
+func TestLeaseStoreExpired(t *testing.T) {
+	s := newLeaseStore()
+
+	s.leases.Store("stale", &lease{expiresAt: time.Now().Add(-time.Second)})
+	s.leases.Store("fresh", &lease{expiresAt: time.Now().Add(time.Minute)})
+
+	expired := s.expired()
+	if len(expired) != 1 || expired[0] != "stale" {
+		t.Fatalf("expected only \"stale\" to be expired, got %v", expired)
+	}
+}
+
+func TestLeaseStoreDelete(t *testing.T) {
+	s := newLeaseStore()
+
+	s.renew("w1", "tenant1", &HealthCheckResponse{}, "v1", 0)
+	s.delete("w1")
+
+	if _, ok := s.get("w1"); ok {
+		t.Fatalf("expected lease to be gone after delete")
+	}
+}
@@ -0,0 +1,126 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// signatureVersionV1 is the only signature scheme implemented today. It's
+// stored on WebhookWorkerModel.SignatureVersion so future schemes can be
+// rolled out per-worker without breaking workers still on v1.
+const signatureVersionV1 = "v1"
+
+// signatureMaxDrift bounds how far a signed timestamp may be from the
+// engine's clock before the signature is rejected, in either direction.
+const signatureMaxDrift = 5 * time.Minute
+
+// signatureNonceTTL is how long a (workerId, timestamp) pair is remembered
+// for replay rejection. checkDrift accepts timestamps up to signatureMaxDrift
+// on either side of the engine's clock, so a signature stays acceptable for
+// up to 2 * signatureMaxDrift after it is first seen; the TTL covers that
+// whole window, and anything outside it is rejected on drift grounds.
+const signatureNonceTTL = 10 * time.Minute
+
+// signRequest computes the X-Hatchet-Signature header for an outgoing
+// request, following the same construction GitHub and Stripe use:
+// hmac_sha256(secret, "<t>.<method>.<path>.<body>").
+func signRequest(secret string, t int64, method, path string, body []byte) string {
+	return hmacHeader(secret, t, []byte(strconv.FormatInt(t, 10)+"."+method+"."+path+"."), body)
+}
+
+// signResponse computes the signature a worker echoes back over its
+// response body: hmac_sha256(secret, "<t>.<body>"). There's no method/path
+// on a response, so the construction drops those fields symmetrically.
+func signResponse(secret string, t int64, body []byte) string {
+	return hmacHeader(secret, t, []byte(strconv.FormatInt(t, 10)+"."), body)
+}
+
+func hmacHeader(secret string, t int64, prefix, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(prefix)
+	mac.Write(body)
+
+	return fmt.Sprintf("t=%d,v1=%s", t, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func parseSignatureHeader(header string) (t int64, v1 string, err error) {
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		switch kv[0] {
+		case "t":
+			t, err = strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return 0, "", fmt.Errorf("could not parse signature timestamp: %w", err)
+			}
+		case "v1":
+			v1 = kv[1]
+		}
+	}
+
+	if v1 == "" {
+		return 0, "", fmt.Errorf("missing v1 signature")
+	}
+
+	return t, v1, nil
+}
+
+// checkDrift rejects a signature timestamp that is more than
+// signatureMaxDrift away from the engine's clock, in either direction.
+func checkDrift(t int64) error {
+	drift := time.Since(time.Unix(t, 0))
+	if drift > signatureMaxDrift || drift < -signatureMaxDrift {
+		return fmt.Errorf("signature timestamp %d outside of allowed %s drift", t, signatureMaxDrift)
+	}
+
+	return nil
+}
+
+func constantTimeEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// nonceCache rejects replayed (workerId, timestamp) pairs within
+// signatureNonceTTL, after which entries are swept to keep the map bounded.
+type nonceCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func newNonceCache() *nonceCache {
+	return &nonceCache{seen: map[string]time.Time{}}
+}
+
+// checkAndStore returns false if (workerId, t) has already been seen within
+// signatureNonceTTL, otherwise records it and returns true.
+func (n *nonceCache) checkAndStore(workerId string, t int64) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := time.Now()
+	key := fmt.Sprintf("%s:%d", workerId, t)
+
+	for k, seenAt := range n.seen {
+		if now.Sub(seenAt) > signatureNonceTTL {
+			delete(n.seen, k)
+		}
+	}
+
+	if _, ok := n.seen[key]; ok {
+		return false
+	}
+
+	n.seen[key] = now
+
+	return true
+}
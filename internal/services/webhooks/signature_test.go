@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hatchet-dev/hatchet/internal/repository/prisma/db"
+)
+
+func TestVerifyRequestSignatureValid(t *testing.T) {
+	c := &WebhooksController{nonces: newNonceCache()}
+	ww := db.WebhookWorkerModel{ID: "w1", Secret: "shh"}
+	body := []byte(`{"hello":"world"}`)
+
+	header := signRequest(ww.Secret, time.Now().Unix(), "POST", "/path", body)
+
+	if err := c.verifyRequestSignature(ww, header, "POST", "/path", body); err != nil {
+		t.Fatalf("expected a valid signature to verify, got %v", err)
+	}
+}
+
+func TestVerifyRequestSignatureExpired(t *testing.T) {
+	c := &WebhooksController{nonces: newNonceCache()}
+	ww := db.WebhookWorkerModel{ID: "w1", Secret: "shh"}
+	body := []byte(`{}`)
+
+	stale := time.Now().Add(-signatureMaxDrift - time.Minute).Unix()
+	header := signRequest(ww.Secret, stale, "POST", "/path", body)
+
+	if err := c.verifyRequestSignature(ww, header, "POST", "/path", body); err == nil {
+		t.Fatalf("expected a stale timestamp to be rejected")
+	}
+}
+
+func TestVerifyRequestSignatureReplayed(t *testing.T) {
+	c := &WebhooksController{nonces: newNonceCache()}
+	ww := db.WebhookWorkerModel{ID: "w1", Secret: "shh"}
+	body := []byte(`{}`)
+
+	header := signRequest(ww.Secret, time.Now().Unix(), "POST", "/path", body)
+
+	if err := c.verifyRequestSignature(ww, header, "POST", "/path", body); err != nil {
+		t.Fatalf("expected first use to verify, got %v", err)
+	}
+	if err := c.verifyRequestSignature(ww, header, "POST", "/path", body); err == nil {
+		t.Fatalf("expected a replayed signature to be rejected")
+	}
+}
+
+func TestVerifyRequestSignatureTampered(t *testing.T) {
+	c := &WebhooksController{nonces: newNonceCache()}
+	ww := db.WebhookWorkerModel{ID: "w1", Secret: "shh"}
+
+	header := signRequest(ww.Secret, time.Now().Unix(), "POST", "/path", []byte(`{"a":1}`))
+
+	if err := c.verifyRequestSignature(ww, header, "POST", "/path", []byte(`{"a":2}`)); err == nil {
+		t.Fatalf("expected a signature over a different body to be rejected")
+	}
+}
+
+func TestVerifyRequestSignatureUnsupportedVersion(t *testing.T) {
+	c := &WebhooksController{nonces: newNonceCache()}
+	ww := db.WebhookWorkerModel{ID: "w1", Secret: "shh", SignatureVersion: "v2"}
+	body := []byte(`{}`)
+
+	header := signRequest(ww.Secret, time.Now().Unix(), "POST", "/path", body)
+
+	if err := c.verifyRequestSignature(ww, header, "POST", "/path", body); err == nil {
+		t.Fatalf("expected an unsupported signature version to be rejected")
+	}
+}
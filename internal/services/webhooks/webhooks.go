@@ -7,34 +7,122 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hatchet-dev/hatchet/internal/config/server"
 	"github.com/hatchet-dev/hatchet/internal/repository"
 	"github.com/hatchet-dev/hatchet/internal/repository/prisma/db"
 	"github.com/hatchet-dev/hatchet/internal/repository/prisma/sqlchelpers"
+	"github.com/hatchet-dev/hatchet/internal/services/webhooks/delivery"
 	"github.com/hatchet-dev/hatchet/pkg/webhook"
 )
 
+// deliveryRetention is how long a completed delivery stays in the in-memory
+// Recents map (and is eligible for replay via HandleReplayDelivery) before
+// GC trims it. Persisted rows in WebhookDelivery outlive this.
+const deliveryRetention = 24 * time.Hour
+
 type WebhooksController struct {
 	sc                  *server.ServerConfig
 	registeredWorkerIds map[string]bool
-	cleanups            []func() error
+	workerCleanups      sync.Map // map[string]func() error, keyed by ww.ID
+	workers             sync.Map // map[string]*webhook.Worker, keyed by ww.ID
+	knownWorkers        sync.Map // map[string]db.WebhookWorkerModel, keyed by ww.ID, used to re-probe open breakers
+	leases              *leaseStore
+	nonces              *nonceCache
+	deliveries          *delivery.Tracker
+	breaker             *breakerStore
 }
 
 func New(sc *server.ServerConfig) *WebhooksController {
-	return &WebhooksController{
+	c := &WebhooksController{
 		sc:                  sc,
 		registeredWorkerIds: map[string]bool{},
+		leases:              newLeaseStore(),
+		nonces:              newNonceCache(),
+		deliveries:          delivery.NewTracker(deliveryRetention),
+		breaker:             newBreakerStore(sc.Webhooks),
 	}
+	c.breaker.setLoader(c.loadBreakerState)
+
+	return c
+}
+
+// Router builds the handler for every route this controller serves: the
+// worker-facing heartbeat lease endpoint and the tenant-facing delivery
+// list/replay endpoints. Routes are matched by hand rather than pulled in
+// from a router dependency, since this controller is the only thing in the
+// tree that needs to mount them.
+func (c *WebhooksController) Router() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/api/v1/webhook-workers/", func(w http.ResponseWriter, r *http.Request) {
+		workerId := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/v1/webhook-workers/"), "/lease")
+		if r.Method != http.MethodPost || workerId == "" || workerId == r.URL.Path {
+			http.NotFound(w, r)
+			return
+		}
+
+		c.HandleWorkerHeartbeat(w, r, workerId)
+	})
+
+	mux.HandleFunc("/api/v1/tenants/", func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.TrimPrefix(r.URL.Path, "/api/v1/tenants/")
+		parts := strings.Split(rest, "/webhook-deliveries")
+		if len(parts) != 2 || parts[0] == "" {
+			http.NotFound(w, r)
+			return
+		}
+		tenantId := parts[0]
+
+		switch {
+		case parts[1] == "" && r.Method == http.MethodGet:
+			c.HandleListDeliveries(w, r, tenantId)
+		case strings.HasSuffix(parts[1], "/replay") && r.Method == http.MethodPost:
+			id := strings.TrimSuffix(strings.TrimPrefix(parts[1], "/"), "/replay")
+			c.HandleReplayDelivery(w, r, tenantId, id)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	return mux
 }
 
 func (c *WebhooksController) Start() (func() error, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
+	var httpServer *http.Server
+	if c.sc.Webhooks.ListenAddr != "" {
+		httpServer = &http.Server{Addr: c.sc.Webhooks.ListenAddr, Handler: c.Router()}
+		go func() {
+			if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				c.sc.Logger.Err(fmt.Errorf("webhook worker http server stopped: %w", err))
+			}
+		}()
+	}
+
+	// bootstrap ticker: discovers new tenants/workers and performs the
+	// one-time GET healthcheck registration. Steady-state liveness is no
+	// longer driven by this ticker -- it's driven by lease expiry below.
 	ticker := time.NewTicker(30 * time.Second)
+
+	// leaseTicker sweeps for workers whose heartbeat lease has expired,
+	// replacing the old per-worker 10s polling goroutine.
+	leaseTicker := time.NewTicker(5 * time.Second)
+
+	// deliveryGCTicker trims completed deliveries out of the in-memory
+	// Recents map once they're older than deliveryRetention.
+	deliveryGCTicker := time.NewTicker(10 * time.Minute)
+
+	// breakerTicker drives half-open probes for any worker whose circuit
+	// breaker is open and due for a retry.
+	breakerTicker := time.NewTicker(5 * time.Second)
+
 	go func() {
 		for {
 			select {
@@ -42,6 +130,12 @@ func (c *WebhooksController) Start() (func() error, error) {
 				if err := c.check(); err != nil {
 					log.Printf("error checking webhooks: %v", err)
 				}
+			case <-leaseTicker.C:
+				c.sweepExpiredLeases()
+			case <-deliveryGCTicker.C:
+				c.deliveries.GC()
+			case <-breakerTicker.C:
+				c.probeOpenBreakers()
 			case <-ctx.Done():
 				return
 			}
@@ -50,17 +144,98 @@ func (c *WebhooksController) Start() (func() error, error) {
 
 	return func() error {
 		cancel()
-
-		for _, cleanup := range c.cleanups {
-			if err := cleanup(); err != nil {
-				return fmt.Errorf("could not cleanup webhook worker: %w", err)
+		ticker.Stop()
+		leaseTicker.Stop()
+		deliveryGCTicker.Stop()
+		breakerTicker.Stop()
+
+		if httpServer != nil {
+			if cerr := httpServer.Close(); cerr != nil {
+				c.sc.Logger.Err(fmt.Errorf("could not close webhook worker http server: %w", cerr))
 			}
 		}
 
-		return nil
+		var err error
+		c.workerCleanups.Range(func(_, v interface{}) bool {
+			if cerr := v.(func() error)(); cerr != nil {
+				err = fmt.Errorf("could not cleanup webhook worker: %w", cerr)
+			}
+			return true
+		})
+
+		return err
 	}, nil
 }
 
+// sweepExpiredLeases opens the circuit breaker for any worker whose
+// heartbeat lease has not been renewed in time. This replaces the old "3
+// failed healthchecks -> hard flip IsActive=false, never look again"
+// policy: the breaker keeps probing on an exponential backoff instead of
+// leaving an intermittently flaky worker marked dead forever.
+func (c *WebhooksController) sweepExpiredLeases() {
+	for _, workerId := range c.leases.expired() {
+		l, ok := c.leases.get(workerId)
+		if !ok {
+			continue
+		}
+
+		c.leases.delete(workerId)
+
+		b := c.breaker.recordFailure(workerId)
+		c.emitTransition(l.tenantId, workerId, b)
+		c.persistBreakerState(context.Background(), l.tenantId, workerId, b)
+
+		isActive := false
+		_, err := c.sc.EngineRepository.Worker().UpdateWorker(context.Background(), l.tenantId, workerId, &repository.UpdateWorkerOpts{
+			IsActive: &isActive,
+		})
+		if err != nil {
+			c.sc.Logger.Err(fmt.Errorf("could not update worker: %v", err))
+		}
+	}
+}
+
+// probeOpenBreakers sends a single half-open healthcheck probe to every
+// worker whose breaker's backoff window has elapsed. A successful probe
+// closes the breaker and restores dispatch; a failed one reopens it with a
+// longer backoff (decorrelated jitter, capped at CircuitBreakerMaxDelay).
+func (c *WebhooksController) probeOpenBreakers() {
+	c.knownWorkers.Range(func(key, value interface{}) bool {
+		workerId := key.(string)
+		ww := value.(db.WebhookWorkerModel)
+
+		if !c.breaker.shouldProbe(workerId) {
+			return true
+		}
+
+		c.breaker.beginProbe(workerId)
+		c.emitTransition(ww.TenantID, workerId, c.breaker.get(workerId))
+
+		h, err := c.healthcheck(ww)
+		if err != nil {
+			b := c.breaker.recordFailure(workerId)
+			c.emitTransition(ww.TenantID, workerId, b)
+			c.persistBreakerState(context.Background(), ww.TenantID, workerId, b)
+			return true
+		}
+
+		b := c.breaker.recordSuccess(workerId)
+		c.emitTransition(ww.TenantID, workerId, b)
+		c.persistBreakerState(context.Background(), ww.TenantID, workerId, b)
+
+		c.leases.renew(workerId, ww.TenantID, h, "", 0)
+
+		isActive := true
+		if _, err := c.sc.EngineRepository.Worker().UpdateWorker(context.Background(), ww.TenantID, workerId, &repository.UpdateWorkerOpts{
+			IsActive: &isActive,
+		}); err != nil {
+			c.sc.Logger.Err(fmt.Errorf("could not update worker: %v", err))
+		}
+
+		return true
+	})
+}
+
 func (c *WebhooksController) check() error {
 	tenants, err := c.sc.EngineRepository.Tenant().ListTenants(context.Background())
 	if err != nil {
@@ -70,25 +245,19 @@ func (c *WebhooksController) check() error {
 	for _, tenant := range tenants {
 		tenantId := sqlchelpers.UUIDToStr(tenant.ID)
 
-		token, err := c.sc.Auth.JWTManager.GenerateTenantToken(context.Background(), tenantId, "webhook-worker")
-		if err != nil {
-			panic(fmt.Errorf("could not generate default token: %v", err))
-		}
-
 		wws, err := c.sc.APIRepository.WebhookWorker().ListWebhookWorkers(context.Background(), tenantId)
 		if err != nil {
 			return fmt.Errorf("could not get webhook workers: %w", err)
 		}
 
 		for _, ww := range wws {
-			cleanup, err := c.run(tenantId, ww, token)
-			if err != nil {
+			// run() mints a token scoped to this specific worker's
+			// actions/workflows -- see generateWorkerToken. There's no
+			// longer a single blanket tenant token handed to every worker.
+			if _, err := c.run(tenantId, ww); err != nil {
 				log.Printf("error running webhook worker: %v", err)
 				continue
 			}
-			if cleanup != nil {
-				c.cleanups = append(c.cleanups, cleanup)
-			}
 		}
 	}
 
@@ -100,12 +269,25 @@ type HealthCheckResponse struct {
 	Workflows []string `json:"workflows"`
 }
 
+// healthcheck issues the one-time bootstrap GET against a webhook worker.
+// The request is signed with ww.Secret so the worker can authenticate the
+// engine, and the worker is required to echo a signature back over its
+// response body so the engine can authenticate the worker in turn -- an
+// unauthenticated healthcheck would let anything that knows ww.URL respond
+// with fake actions/workflows and register bogus workflows under the tenant.
 func (c *WebhooksController) healthcheck(ww db.WebhookWorkerModel) (*HealthCheckResponse, error) {
 	req, err := http.NewRequest("GET", ww.URL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("could not create request: %w", err)
 	}
 
+	t := time.Now().Unix()
+	path := "/"
+	if u, err := url.Parse(ww.URL); err == nil {
+		path = u.Path
+	}
+	req.Header.Set("X-Hatchet-Signature", signRequest(ww.Secret, t, req.Method, path, nil))
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("could not send request: %w", err)
@@ -122,6 +304,10 @@ func (c *WebhooksController) healthcheck(ww db.WebhookWorkerModel) (*HealthCheck
 		return nil, fmt.Errorf("could not read response body: %w", err)
 	}
 
+	if err := c.verifyResponseSignature(ww, resp.Header.Get("X-Hatchet-Signature"), body); err != nil {
+		return nil, fmt.Errorf("could not verify healthcheck response: %w", err)
+	}
+
 	var res HealthCheckResponse
 	err = json.Unmarshal(body, &res)
 	if err != nil {
@@ -131,131 +317,412 @@ func (c *WebhooksController) healthcheck(ww db.WebhookWorkerModel) (*HealthCheck
 	return &res, nil
 }
 
-func (c *WebhooksController) run(tenantId string, ww db.WebhookWorkerModel, token string) (func() error, error) {
-	h, err := c.healthcheck(ww)
+// verifyResponseSignature checks the signature a worker echoed back over a
+// healthcheck response, rejecting stale timestamps and replays.
+func (c *WebhooksController) verifyResponseSignature(ww db.WebhookWorkerModel, header string, body []byte) error {
+	if ww.SignatureVersion != "" && ww.SignatureVersion != signatureVersionV1 {
+		return fmt.Errorf("unsupported signature version %q", ww.SignatureVersion)
+	}
+
+	t, sig, err := parseSignatureHeader(header)
 	if err != nil {
-		return nil, fmt.Errorf("webhook worker %s of tenant %s healthcheck failed: %w", ww.ID, tenantId, err)
+		return err
+	}
+
+	if err := checkDrift(t); err != nil {
+		return err
+	}
+
+	_, expected, _ := parseSignatureHeader(signResponse(ww.Secret, t, body))
+	if !constantTimeEqual(sig, expected) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	if !c.nonces.checkAndStore(ww.ID, t) {
+		return fmt.Errorf("replayed signature for worker %s at t=%d", ww.ID, t)
+	}
+
+	return nil
+}
+
+// verifyRequestSignature checks the signature a worker attaches to an
+// incoming request (e.g. a heartbeat POST), mirroring verifyResponseSignature
+// for the opposite direction. Only SignatureVersion "v1" is supported today;
+// ww.SignatureVersion exists so future schemes can be rolled out per-worker.
+func (c *WebhooksController) verifyRequestSignature(ww db.WebhookWorkerModel, header, method, path string, body []byte) error {
+	if ww.SignatureVersion != "" && ww.SignatureVersion != signatureVersionV1 {
+		return fmt.Errorf("unsupported signature version %q", ww.SignatureVersion)
+	}
+
+	t, sig, err := parseSignatureHeader(header)
+	if err != nil {
+		return err
+	}
+
+	if err := checkDrift(t); err != nil {
+		return err
+	}
+
+	_, expected, _ := parseSignatureHeader(signRequest(ww.Secret, t, method, path, body))
+	if !constantTimeEqual(sig, expected) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	if !c.nonces.checkAndStore(ww.ID, t) {
+		return fmt.Errorf("replayed signature for worker %s at t=%d", ww.ID, t)
+	}
+
+	return nil
+}
+
+// HeartbeatRequest is the body a pkg/webhook.Worker POSTs periodically to
+// keep its lease alive. It carries the same shape as HealthCheckResponse
+// plus fields the one-time bootstrap GET can't express.
+type HeartbeatRequest struct {
+	Actions   []string `json:"actions"`
+	Workflows []string `json:"workflows"`
+	Version   string   `json:"version"`
+	Capacity  int      `json:"capacity"`
+}
+
+// HeartbeatResponse carries backpressure back to the worker: the set of
+// workflow IDs currently assigned to it. A worker that's being drained ahead
+// of cleanup sees its assignments shrink across heartbeats instead of being
+// killed mid-run.
+type HeartbeatResponse struct {
+	AssignedWorkflowIDs []string `json:"assignedWorkflowIds"`
+}
+
+// HandleWorkerHeartbeat serves POST /api/v1/webhook-workers/{id}/lease
+// (mounted by Router, and served by Start's HTTP server when
+// WebhooksConfig.ListenAddr is set). It renews the worker's lease and, if the reported
+// actions/workflows hash has changed since the last heartbeat, restarts the
+// worker in place -- no fresh outbound healthcheck round-trip required.
+func (c *WebhooksController) HandleWorkerHeartbeat(w http.ResponseWriter, r *http.Request, workerId string) {
+	ww, err := c.sc.APIRepository.WebhookWorker().GetWebhookWorkerById(r.Context(), workerId)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not find webhook worker: %v", err), http.StatusNotFound)
+		return
+	}
+
+	if err := c.validateWorkerToken(r.Context(), ww, r.Header.Get("Authorization")); err != nil {
+		http.Error(w, fmt.Sprintf("could not validate webhook worker token: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not read heartbeat body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := c.verifyRequestSignature(ww, r.Header.Get("X-Hatchet-Signature"), r.Method, r.URL.Path, body); err != nil {
+		http.Error(w, fmt.Sprintf("could not verify heartbeat signature: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	var req HeartbeatRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, fmt.Sprintf("could not decode heartbeat body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	tenantId := ww.TenantID
+
+	c.knownWorkers.Store(workerId, ww)
+
+	h := &HealthCheckResponse{Actions: req.Actions, Workflows: req.Workflows}
+
+	prev := c.leases.renew(workerId, tenantId, h, req.Version, req.Capacity)
+
+	if prev != nil && (prev.actionsHash != hash(req.Actions) || prev.workflowsHash != hash(req.Workflows)) {
+		if err := c.restart(tenantId, ww, h); err != nil {
+			c.sc.Logger.Err(fmt.Errorf("could not restart webhook worker after heartbeat: %v", err))
+		}
+	}
+
+	wasOpen := c.breaker.get(workerId).state != breakerClosed
+	b := c.breaker.recordSuccess(workerId)
+	if wasOpen {
+		c.emitTransition(tenantId, workerId, b)
+	}
+
+	isActive := true
+	if _, err := c.sc.EngineRepository.Worker().UpdateWorker(r.Context(), tenantId, workerId, &repository.UpdateWorkerOpts{
+		IsActive: &isActive,
+	}); err != nil {
+		c.sc.Logger.Err(fmt.Errorf("could not update worker: %v", err))
+	}
+
+	assigned, err := c.sc.EngineRepository.Worker().ListAssignedWorkflowRunIds(r.Context(), tenantId, workerId)
+	if err != nil {
+		c.sc.Logger.Err(fmt.Errorf("could not list assigned workflow runs: %v", err))
+	}
+
+	writeSignedJSON(w, ww.Secret, &HeartbeatResponse{AssignedWorkflowIDs: assigned})
+}
+
+// writeSignedJSON encodes v as the response body and echoes a signature over
+// it in X-Hatchet-Signature, the same pattern healthcheck() expects on its
+// own responses.
+func writeSignedJSON(w http.ResponseWriter, secret string, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("could not marshal json response: %v", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Hatchet-Signature", signResponse(secret, time.Now().Unix(), body))
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(body); err != nil {
+		log.Printf("could not write json response: %v", err)
+	}
+}
+
+// HandleListDeliveries serves GET /api/v1/tenants/{t}/webhook-deliveries,
+// optionally filtered by worker/status/action query params. It reads from
+// the persisted WebhookDelivery table, not the in-memory Tracker, so it
+// reflects deliveries beyond the live Recents retention window too.
+func (c *WebhooksController) HandleListDeliveries(w http.ResponseWriter, r *http.Request, tenantId string) {
+	q := r.URL.Query()
+
+	opts := &repository.ListWebhookDeliveriesOpts{
+		WorkerID: q.Get("worker"),
+		Status:   q.Get("status"),
+		Action:   q.Get("action"),
+	}
+
+	deliveries, err := c.sc.APIRepository.WebhookDelivery().ListWebhookDeliveries(r.Context(), tenantId, opts)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not list webhook deliveries: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, deliveries)
+}
+
+// HandleReplayDelivery serves POST
+// /api/v1/tenants/{t}/webhook-deliveries/{id}/replay. It re-dispatches the
+// original payload to the same worker that received it, using whichever
+// *webhook.Worker instance is currently registered for WorkerID (after a
+// restart, that is the restarted instance). Replay fails only if no Worker
+// has been started for WorkerID; entries are not removed when a worker is
+// marked inactive.
+func (c *WebhooksController) HandleReplayDelivery(w http.ResponseWriter, r *http.Request, tenantId, id string) {
+	d, ok := c.deliveries.Get(id)
+	if !ok {
+		http.Error(w, fmt.Sprintf("delivery %s is outside the replay retention window", id), http.StatusNotFound)
+		return
 	}
 
+	if d.TenantID != tenantId {
+		http.Error(w, fmt.Sprintf("delivery %s does not belong to tenant %s", id, tenantId), http.StatusNotFound)
+		return
+	}
+
+	v, ok := c.workers.Load(d.WorkerID)
+	if !ok {
+		http.Error(w, fmt.Sprintf("worker %s for delivery %s is no longer registered", d.WorkerID, id), http.StatusConflict)
+		return
+	}
+	wk := v.(*webhook.Worker)
+
+	replayID, err := wk.Replay(r.Context(), d.Action, d.WorkflowRunID, d.RequestBody)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not replay delivery %s: %v", id, err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]string{"deliveryId": replayID})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("could not write json response: %v", err)
+	}
+}
+
+// persistDelivery writes a completed delivery to the WebhookDelivery table,
+// passed to pkg/webhook.Worker as OnDeliveryComplete so HandleListDeliveries
+// can see deliveries beyond the in-memory Tracker's retention window.
+func (c *WebhooksController) persistDelivery(d *delivery.Delivery) {
+	_, err := c.sc.APIRepository.WebhookDelivery().CreateWebhookDelivery(context.Background(), &db.WebhookDeliveryModel{
+		ID:            d.ID,
+		TenantID:      d.TenantID,
+		WorkerID:      d.WorkerID,
+		Action:        d.Action,
+		WorkflowRunID: d.WorkflowRunID,
+		StartedAt:     d.StartedAt,
+		EndedAt:       d.EndedAt,
+		StatusCode:    d.StatusCode,
+		AttemptCount:  d.AttemptCount,
+		RequestHash:   d.RequestHash,
+		ResponseBody:  d.ResponseBody,
+		Error:         d.Error,
+	})
+	if err != nil {
+		c.sc.Logger.Err(fmt.Errorf("could not persist webhook delivery %s: %v", d.ID, err))
+	}
+}
+
+// run performs the one-time bootstrap GET healthcheck against a webhook
+// worker and starts it. Ongoing liveness and drift detection is no longer
+// driven from here -- it's driven by the heartbeats the worker pushes to
+// HandleWorkerHeartbeat, which renew the worker's lease in c.leases and
+// trigger restart() directly when the actions/workflows hash changes.
+func (c *WebhooksController) run(tenantId string, ww db.WebhookWorkerModel) (func() error, error) {
+	// Store ww -- and therefore make it visible to probeOpenBreakers and
+	// breakerStore.get's persisted-state loader -- regardless of whether
+	// the bootstrap healthcheck below succeeds. A worker that's down or
+	// flaky across a controller restart would otherwise never appear in
+	// knownWorkers (it only passed through here on a *successful*
+	// healthcheck before), so its persisted breaker state could never be
+	// resumed. The bootstrap GET below is not gated by the breaker: it runs
+	// on every check() tick until it succeeds once.
+	c.knownWorkers.Store(ww.ID, ww)
+
+	// Once a worker is registered and heartbeating, steady-state liveness
+	// comes entirely from the lease/heartbeat path -- skip the bootstrap GET
+	// on every tick instead of polling every worker every 30s forever.
 	if _, ok := c.registeredWorkerIds[ww.ID]; ok {
 		return nil, nil
 	}
+
+	h, err := c.healthcheck(ww)
+	if err != nil {
+		return nil, fmt.Errorf("webhook worker %s of tenant %s healthcheck failed: %w", ww.ID, tenantId, err)
+	}
+
 	c.registeredWorkerIds[ww.ID] = true
 
+	cleanup, err := c.startWorker(tenantId, ww, h)
+	if err != nil {
+		return nil, err
+	}
+
+	c.workerCleanups.Store(ww.ID, cleanup)
+	c.leases.renew(ww.ID, tenantId, h, "", 0)
+
+	wasOpen := c.breaker.get(ww.ID).state != breakerClosed
+	b := c.breaker.recordSuccess(ww.ID)
+	if wasOpen {
+		c.emitTransition(tenantId, ww.ID, b)
+	}
+
+	return cleanup, nil
+}
+
+// startWorker mints a token scoped to ww's current actions/workflows and
+// uses it to construct and start the pkg/webhook.Worker, without touching
+// registeredWorkerIds or the lease store. It's shared between the initial
+// bootstrap in run() and the restart path in HandleWorkerHeartbeat, so every
+// restart rotates the worker's token along with its actions/workflows.
+func (c *WebhooksController) startWorker(tenantId string, ww db.WebhookWorkerModel, h *HealthCheckResponse) (func() error, error) {
+	token, err := c.generateWorkerToken(tenantId, ww.ID, h)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate webhook worker token: %w", err)
+	}
+
 	w, err := webhook.NewWorker(webhook.WorkerOpts{
-		Token:     token,
-		ID:        ww.ID,
-		Secret:    ww.Secret,
-		URL:       ww.URL,
-		TenantID:  tenantId,
-		Actions:   h.Actions,
-		Workflows: h.Workflows,
+		EngineURL:          c.engineURL(),
+		Token:              token,
+		ID:                 ww.ID,
+		Secret:             ww.Secret,
+		URL:                ww.URL,
+		TenantID:           tenantId,
+		Actions:            h.Actions,
+		Workflows:          h.Workflows,
+		Deliveries:         c.deliveries,
+		OnDeliveryComplete: c.persistDelivery,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("could not create webhook worker: %w", err)
 	}
 
-	var cleanups []func() error
-
 	cleanup, err := w.Start()
 	if err != nil {
 		return nil, fmt.Errorf("could not start webhook worker: %w", err)
 	}
 
-	cleanups = append(cleanups, cleanup)
-
-	ctx, cancel := context.WithCancel(context.Background())
+	c.workers.Store(ww.ID, w)
 
-	go func() {
-		timer := time.NewTimer(10 * time.Second)
-		defer timer.Stop()
+	return cleanup, nil
+}
 
-		wfsHashLast := hash(h.Workflows)
-		actionsHashLast := hash(h.Actions)
+// generateWorkerToken mints a JWT scoped to exactly this worker's reported
+// actions/workflows, replacing the old scheme where GenerateTenantToken
+// handed the same blanket tenant token to every webhook worker in a tenant
+// -- a compromised worker could use that token to impersonate any other
+// worker in the tenant. The token expires in an hour, forcing a rotation on
+// the next heartbeat well before that.
+func (c *WebhooksController) generateWorkerToken(tenantId, workerId string, h *HealthCheckResponse) (string, error) {
+	return c.sc.Auth.JWTManager.GenerateWebhookWorkerToken(context.Background(), tenantId, workerId, &repository.WebhookWorkerTokenClaims{
+		Actions:   h.Actions,
+		Workflows: h.Workflows,
+	})
+}
 
-		healthCheckErrors := 0
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-timer.C:
-				h, err := c.healthcheck(ww)
-				if err != nil {
-					healthCheckErrors++
-					if healthCheckErrors > 3 {
-						c.sc.Logger.Printf("webhook worker %s of tenant %s failed 3 health checks, marking as inactive", ww.ID, tenantId)
-
-						isActive := false
-						_, err := c.sc.EngineRepository.Worker().UpdateWorker(context.Background(), tenantId, ww.ID, &repository.UpdateWorkerOpts{
-							IsActive: &isActive,
-						})
-						if err != nil {
-							c.sc.Logger.Err(fmt.Errorf("could not update worker: %v", err))
-						}
-					} else {
-						c.sc.Logger.Printf("webhook worker %s of tenant %s failed one health check, retrying...", ww.ID, tenantId)
-					}
-					continue
-				}
+// validateWorkerToken checks that the bearer token on an incoming RPC from a
+// webhook worker is scoped to exactly that worker -- claims.Sub must match
+// ww.ID and the audience must be "webhook" -- mirroring the way forgejo
+// scopes its actions-runner JWT to a single runner. This is what stops a
+// compromised webhook endpoint from using its token to act as a different
+// worker in the same tenant.
+func (c *WebhooksController) validateWorkerToken(ctx context.Context, ww db.WebhookWorkerModel, authHeader string) error {
+	token := strings.TrimPrefix(authHeader, "Bearer ")
+	if token == "" {
+		return fmt.Errorf("missing bearer token")
+	}
 
-				wfsHash := hash(h.Workflows)
-				actionsHash := hash(h.Actions)
-
-				log.Printf("wfsHash %s, wfsHashLast %s", wfsHash, wfsHashLast)
-				log.Printf("actionsHash %s, actionsHashLast %s", actionsHash, actionsHashLast)
-
-				if wfsHash != wfsHashLast || actionsHash != actionsHashLast {
-					// update the webhook workflow, and restart worker
-					log.Printf("webhook worker %s of tenant %s has changed, updating...", ww.ID, tenantId)
-					// TODO
-					for _, cleanup := range cleanups {
-						if err := cleanup(); err != nil {
-							c.sc.Logger.Err(fmt.Errorf("could not cleanup webhook worker: %v", err))
-						}
-					}
-
-					newCleanup, err := c.run(tenantId, ww, token)
-					if err != nil {
-						c.sc.Logger.Err(fmt.Errorf("could not restart webhook worker: %v", err))
-					}
-					cleanups = []func() error{newCleanup}
-					return
-				}
+	claims, err := c.sc.Auth.JWTManager.ValidateWebhookWorkerToken(ctx, token)
+	if err != nil {
+		return fmt.Errorf("could not validate token: %w", err)
+	}
 
-				wfsHashLast = wfsHash
-				actionsHashLast = actionsHash
+	if claims.Sub != ww.ID || claims.Aud != "webhook" {
+		return fmt.Errorf("token is not scoped to webhook worker %s", ww.ID)
+	}
 
-				if healthCheckErrors > 0 {
-					c.sc.Logger.Printf("webhook worker %s is healthy again", ww.ID)
-				}
+	return nil
+}
 
-				isActive := true
-				_, err = c.sc.EngineRepository.Worker().UpdateWorker(context.Background(), tenantId, ww.ID, &repository.UpdateWorkerOpts{
-					IsActive: &isActive,
-				})
-				if err != nil {
-					c.sc.Logger.Err(fmt.Errorf("could not update worker: %v", err))
-				}
+// restart tears down the currently running worker for ww and starts a fresh
+// one -- with a freshly rotated, rescoped token -- with the updated
+// actions/workflows from h, replacing its entry in c.workerCleanups.
+func (c *WebhooksController) restart(tenantId string, ww db.WebhookWorkerModel, h *HealthCheckResponse) error {
+	c.sc.Logger.Printf("webhook worker %s of tenant %s has changed, restarting...", ww.ID, tenantId)
 
-				healthCheckErrors = 0
-			}
+	if v, ok := c.workerCleanups.Load(ww.ID); ok {
+		if err := v.(func() error)(); err != nil {
+			c.sc.Logger.Err(fmt.Errorf("could not cleanup webhook worker before restart: %v", err))
 		}
-	}()
+	}
 
-	return func() error {
-		cancel()
-		for _, cleanup := range cleanups {
-			if err := cleanup(); err != nil {
-				return fmt.Errorf("could not cleanup webhook worker: %w", err)
-			}
-		}
+	newCleanup, err := c.startWorker(tenantId, ww, h)
+	if err != nil {
+		return fmt.Errorf("could not restart webhook worker: %w", err)
+	}
 
-		return nil
-	}, nil
+	c.workerCleanups.Store(ww.ID, newCleanup)
+
+	return nil
+}
+
+// engineURL returns the base URL a pkg/webhook.Worker should heartbeat
+// against, derived from the address this controller's own HTTP server
+// binds. Empty if the controller isn't serving routes (e.g. in tests).
+func (c *WebhooksController) engineURL() string {
+	if c.sc.Webhooks.ListenAddr == "" {
+		return ""
+	}
+
+	return "http://" + c.sc.Webhooks.ListenAddr
 }
 
 func hash(s []string) string {
 	n := s
 	slices.Sort(n)
 	return strings.Join(n, ",")
-}
\ No newline at end of file
+}
@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signatureMaxDrift mirrors internal/services/webhooks.signatureMaxDrift --
+// the two packages are independent implementations of the same wire
+// protocol, each on its own side of the engine/worker boundary.
+const signatureMaxDrift = 5 * time.Minute
+
+func parseSignatureHeader(header string) (t int64, v1 string, err error) {
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		switch kv[0] {
+		case "t":
+			t, err = strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return 0, "", fmt.Errorf("could not parse signature timestamp: %w", err)
+			}
+		case "v1":
+			v1 = kv[1]
+		}
+	}
+
+	if v1 == "" {
+		return 0, "", fmt.Errorf("missing v1 signature")
+	}
+
+	return t, v1, nil
+}
+
+func checkDrift(t int64) error {
+	drift := time.Since(time.Unix(t, 0))
+	if drift > signatureMaxDrift || drift < -signatureMaxDrift {
+		return fmt.Errorf("signature timestamp %d outside of allowed %s drift", t, signatureMaxDrift)
+	}
+
+	return nil
+}
+
+func constantTimeEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// verifyResponseSignature checks the signature the dispatch target echoed
+// back over its response body, mirroring
+// internal/services/webhooks.verifyResponseSignature for the direction
+// pkg/webhook is on: the engine signs every outgoing healthcheck and
+// dispatch, and both must get a signed response back before trusting it.
+func verifyResponseSignature(secret, header string, body []byte) error {
+	t, sig, err := parseSignatureHeader(header)
+	if err != nil {
+		return err
+	}
+
+	if err := checkDrift(t); err != nil {
+		return err
+	}
+
+	_, expected, _ := parseSignatureHeader(signResponse(secret, t, body))
+	if !constantTimeEqual(sig, expected) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+// signResponse mirrors internal/services/webhooks.signResponse.
+func signResponse(secret string, t int64, body []byte) string {
+	return hmacHeader(secret, t, []byte(strconv.FormatInt(t, 10)+"."), body)
+}
@@ -0,0 +1,308 @@
+// Package webhook is the worker-facing half of the webhook dispatch
+// protocol whose engine-facing half lives in internal/services/webhooks.
+// A Worker represents one tenant's registered webhook endpoint: it pushes
+// signed heartbeats to keep its lease alive and dispatches actions to the
+// endpoint's URL, recording each dispatch in a delivery.Tracker.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hatchet-dev/hatchet/internal/services/webhooks/delivery"
+)
+
+// heartbeatInterval is how often a Worker pushes a heartbeat to keep its
+// lease alive. It must stay comfortably under the engine's lease TTL
+// (internal/services/webhooks.defaultLeaseTTL, 30s) so a single missed
+// heartbeat doesn't immediately expire the lease.
+const heartbeatInterval = 10 * time.Second
+
+// WorkerOpts configures a Worker.
+type WorkerOpts struct {
+	// EngineURL is the base URL of the engine's webhook-facing HTTP server
+	// (internal/config/server.WebhooksConfig.ListenAddr), used to heartbeat.
+	EngineURL string
+	// Token is the scoped bearer token minted for this worker by
+	// internal/services/webhooks.generateWorkerToken.
+	Token     string
+	ID        string
+	Secret    string
+	URL       string
+	TenantID  string
+	Actions   []string
+	Workflows []string
+
+	// Deliveries records every dispatch this Worker makes, shared with the
+	// controller so the list/replay API can see live in-flight deliveries
+	// without a database round-trip.
+	Deliveries *delivery.Tracker
+
+	// OnDeliveryComplete, if set, is called once a dispatch finishes (or
+	// fails), after the delivery has been recorded in Deliveries. The
+	// controller uses it to persist the delivery via
+	// repository.WebhookDeliveryRepository, so the list API can see
+	// deliveries beyond Deliveries' in-memory retention window.
+	OnDeliveryComplete func(*delivery.Delivery)
+}
+
+// Worker pushes heartbeats for, and dispatches actions to, one tenant's
+// registered webhook endpoint.
+type Worker struct {
+	opts WorkerOpts
+
+	client *http.Client
+
+	mu       sync.Mutex
+	capacity int
+}
+
+// NewWorker constructs a Worker from opts. It does not start heartbeating
+// or accept dispatches until Start is called.
+func NewWorker(opts WorkerOpts) (*Worker, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("webhook worker requires a URL")
+	}
+	if opts.Secret == "" {
+		return nil, fmt.Errorf("webhook worker requires a secret")
+	}
+
+	return &Worker{
+		opts:     opts,
+		client:   http.DefaultClient,
+		capacity: 0,
+	}, nil
+}
+
+// Start begins the Worker's heartbeat loop and returns a cleanup func that
+// stops it.
+func (w *Worker) Start() (func() error, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ticker := time.NewTicker(heartbeatInterval)
+
+	go func() {
+		// Heartbeat immediately on start rather than waiting a full
+		// interval, so the lease is renewed well before the bootstrap
+		// healthcheck's initial renew() expires.
+		w.heartbeat(ctx)
+
+		for {
+			select {
+			case <-ticker.C:
+				w.heartbeat(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return func() error {
+		cancel()
+		ticker.Stop()
+		return nil
+	}, nil
+}
+
+type heartbeatRequest struct {
+	Actions   []string `json:"actions"`
+	Workflows []string `json:"workflows"`
+	Version   string   `json:"version"`
+	Capacity  int      `json:"capacity"`
+}
+
+type heartbeatResponse struct {
+	AssignedWorkflowIDs []string `json:"assignedWorkflowIds"`
+}
+
+// heartbeat POSTs a signed HeartbeatRequest to the engine's lease endpoint.
+// Failures are non-fatal: if the engine doesn't hear back in time its lease
+// sweep will open the circuit breaker and probe again later, the same as
+// any other missed heartbeat.
+func (w *Worker) heartbeat(ctx context.Context) {
+	if w.opts.EngineURL == "" {
+		return
+	}
+
+	body, err := json.Marshal(heartbeatRequest{
+		Actions:   w.opts.Actions,
+		Workflows: w.opts.Workflows,
+		Version:   "v1",
+		Capacity:  w.capacityValue(),
+	})
+	if err != nil {
+		return
+	}
+
+	path := fmt.Sprintf("/api/v1/webhook-workers/%s/lease", w.opts.ID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.opts.EngineURL+path, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	t := time.Now().Unix()
+	req.Header.Set("Authorization", "Bearer "+w.opts.Token)
+	req.Header.Set("X-Hatchet-Signature", signRequest(w.opts.Secret, t, req.Method, path, body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return
+	}
+
+	var hr heartbeatResponse
+	_ = json.Unmarshal(respBody, &hr)
+}
+
+func (w *Worker) capacityValue() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.capacity
+}
+
+// Dispatch sends action's payload to the worker's URL, recording the
+// attempt in Deliveries from enqueue through completion.
+func (w *Worker) Dispatch(ctx context.Context, action, workflowRunID string, payload []byte) (string, error) {
+	// The token minted for this worker (auth.JWTManager.GenerateWebhookWorkerToken)
+	// carries w.opts.Actions as its action claims: don't dispatch -- or
+	// replay -- an action this worker's current token was never scoped to,
+	// e.g. one dropped from its healthcheck response by a restart that
+	// raced with an in-flight replay.
+	if !slices.Contains(w.opts.Actions, action) {
+		return "", fmt.Errorf("worker %s is not scoped to action %q", w.opts.ID, action)
+	}
+
+	id, err := newDeliveryID()
+	if err != nil {
+		return "", fmt.Errorf("could not generate delivery id: %w", err)
+	}
+
+	d := &delivery.Delivery{
+		ID:            id,
+		WorkerID:      w.opts.ID,
+		TenantID:      w.opts.TenantID,
+		Action:        action,
+		WorkflowRunID: workflowRunID,
+		StartedAt:     time.Now(),
+		// AttemptCount is 1 because doDispatch makes exactly one HTTP
+		// attempt per Dispatch call -- there's no internal retry here.
+		// Replay creates a new Delivery (and a new ID) rather than bumping
+		// this one, the same as a fresh dispatch.
+		AttemptCount: 1,
+		RequestHash:  hashBody(payload),
+		RequestBody:  payload,
+	}
+
+	if w.opts.Deliveries != nil {
+		w.opts.Deliveries.Enqueue(d)
+		w.opts.Deliveries.Start(d)
+	}
+
+	statusCode, respBody, dispatchErr := w.doDispatch(ctx, payload)
+
+	if w.opts.Deliveries != nil {
+		w.opts.Deliveries.Complete(id, statusCode, respBody, dispatchErr)
+	}
+
+	if w.opts.Deliveries != nil && w.opts.OnDeliveryComplete != nil {
+		if d, ok := w.opts.Deliveries.Get(id); ok {
+			w.opts.OnDeliveryComplete(d)
+		}
+	}
+
+	if dispatchErr != nil {
+		return "", dispatchErr
+	}
+
+	return id, nil
+}
+
+// Replay re-dispatches a previously delivered payload, producing a fresh
+// delivery ID -- see internal/services/webhooks.HandleReplayDelivery.
+func (w *Worker) Replay(ctx context.Context, action, workflowRunID string, body []byte) (string, error) {
+	return w.Dispatch(ctx, action, workflowRunID, body)
+}
+
+func (w *Worker) doDispatch(ctx context.Context, payload []byte) (statusCode int, respBody []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.opts.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not create request: %w", err)
+	}
+
+	t := time.Now().Unix()
+	path := "/"
+	if u, err := url.Parse(w.opts.URL); err == nil {
+		path = u.Path
+	}
+	req.Header.Set("X-Hatchet-Signature", signRequest(w.opts.Secret, t, req.Method, path, payload))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("could not read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, respBody, fmt.Errorf("dispatch failed with status code %d", resp.StatusCode)
+	}
+
+	if err := verifyResponseSignature(w.opts.Secret, resp.Header.Get("X-Hatchet-Signature"), respBody); err != nil {
+		return resp.StatusCode, respBody, fmt.Errorf("could not verify dispatch response: %w", err)
+	}
+
+	return resp.StatusCode, respBody, nil
+}
+
+func newDeliveryID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func hashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// signRequest mirrors internal/services/webhooks.signRequest -- the two
+// packages independently implement the same wire construction (the same
+// way two ends of a webhook contract always do) rather than one importing
+// the other.
+func signRequest(secret string, t int64, method, path string, body []byte) string {
+	return hmacHeader(secret, t, []byte(strconv.FormatInt(t, 10)+"."+method+"."+path+"."), body)
+}
+
+func hmacHeader(secret string, t int64, prefix, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(prefix)
+	mac.Write(body)
+
+	return fmt.Sprintf("t=%d,v1=%s", t, hex.EncodeToString(mac.Sum(nil)))
+}